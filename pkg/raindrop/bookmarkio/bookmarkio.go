@@ -0,0 +1,55 @@
+// Package bookmarkio decodes and encodes bookmark collections in the
+// Netscape bookmarks.html format (used by most browsers' export/import)
+// and in Raindrop.io's bookmark CSV format.
+//
+// Bookmark and Folder are independent of package raindrop's data model, so
+// that model's Client.ImportBookmarks and Client.ExportBookmarks convert to
+// and from raindrop.Raindrop / raindrop.Collection at the boundary.
+package bookmarkio
+
+import "time"
+
+// Format identifies a bookmark file format this package can decode/encode.
+type Format int
+
+const (
+	// FormatNetscape is the Netscape bookmarks.html format.
+	FormatNetscape Format = iota
+	// FormatCSV is Raindrop.io's bookmark CSV format
+	// (url,folder,title,note,tags,created).
+	FormatCSV
+)
+
+// Bookmark is a single decoded bookmark entry.
+type Bookmark struct {
+	URL     string
+	Title   string
+	Excerpt string
+	Tags    []string
+	Created time.Time
+	// FolderID is the synthetic ID (see Folder) of the folder this
+	// bookmark was found in, or 0 if it wasn't in any folder.
+	FolderID uint32
+}
+
+// Folder is a decoded bookmark folder.
+type Folder struct {
+	// ID is synthetic, assigned by the Decoder in document order starting
+	// at 1.
+	ID uint32
+	// ParentID is the synthetic ID of the parent folder, or 0 for a
+	// root-level folder.
+	ParentID uint32
+	Title    string
+}
+
+// Decoder decodes a bookmark file into its bookmarks and the folder
+// hierarchy implied by it.
+type Decoder interface {
+	Decode() ([]Bookmark, []Folder, error)
+}
+
+// Encoder encodes bookmarks and their folder hierarchy to a bookmark file.
+type Encoder interface {
+	Encode(bookmarks []Bookmark, folders []Folder) error
+}
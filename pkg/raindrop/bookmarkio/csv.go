@@ -0,0 +1,123 @@
+package bookmarkio
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+)
+
+var csvColumns = []string{"url", "folder", "title", "note", "tags", "created"}
+
+// CSVDecoder parses Raindrop.io's bookmark CSV format
+// (url,folder,title,note,tags,created) into Bookmarks. Unlike
+// NetscapeDecoder, folders are flat: each row's folder column is a single
+// name, not a path, so there's no nested hierarchy to reconstruct.
+type CSVDecoder struct {
+	r io.Reader
+}
+
+// NewCSVDecoder returns a CSVDecoder that reads from r.
+func NewCSVDecoder(r io.Reader) *CSVDecoder {
+	return &CSVDecoder{r: r}
+}
+
+// Decode parses the CSV document. The header row determines column order;
+// missing columns are left zero-valued. Folder IDs are synthetic, assigned
+// in row order starting at 1; 0 means a bookmark's folder column was empty.
+func (d *CSVDecoder) Decode() ([]Bookmark, []Folder, error) {
+	cr := csv.NewReader(d.r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	registry := newFolderRegistry()
+	var bookmarks []Bookmark
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		b := Bookmark{
+			URL:     csvField(record, index, "url"),
+			Title:   csvField(record, index, "title"),
+			Excerpt: csvField(record, index, "note"),
+			Tags:    splitTags(csvField(record, index, "tags")),
+			Created: parseCSVCreated(csvField(record, index, "created")),
+		}
+		if folder := csvField(record, index, "folder"); folder != "" {
+			b.FolderID = registry.ensure("", folder, 0)
+		}
+		bookmarks = append(bookmarks, b)
+	}
+
+	return bookmarks, registry.folders, nil
+}
+
+func csvField(record []string, index map[string]int, name string) string {
+	i, ok := index[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+func parseCSVCreated(raw string) time.Time {
+	if raw == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// CSVEncoder writes Bookmarks to Raindrop.io's bookmark CSV format
+// (url,folder,title,note,tags,created).
+type CSVEncoder struct {
+	w io.Writer
+}
+
+// NewCSVEncoder returns a CSVEncoder that writes to w.
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{w: w}
+}
+
+// Encode writes bookmarks as CSV, resolving each Bookmark's FolderID to a
+// folder name via folders. Bookmarks with FolderID 0 are written with an
+// empty folder column.
+func (e *CSVEncoder) Encode(bookmarks []Bookmark, folders []Folder) error {
+	titleByID := make(map[uint32]string, len(folders))
+	for _, f := range folders {
+		titleByID[f.ID] = f.Title
+	}
+
+	cw := csv.NewWriter(e.w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, b := range bookmarks {
+		created := ""
+		if !b.Created.IsZero() {
+			created = b.Created.Format(time.RFC3339)
+		}
+		row := []string{b.URL, titleByID[b.FolderID], b.Title, b.Excerpt, strings.Join(b.Tags, ","), created}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,55 @@
+package bookmarkio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	folders := []Folder{
+		{ID: 1, ParentID: 0, Title: "Reading"},
+	}
+	bookmarks := []Bookmark{
+		{URL: "https://example.com", Title: "Example", Excerpt: "an excerpt", Tags: []string{"go", "test"}, Created: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), FolderID: 1},
+		{URL: "https://golang.org", Title: "Go", FolderID: 0},
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVEncoder(&buf).Encode(bookmarks, folders); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	gotBookmarks, gotFolders, err := NewCSVDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode() returned error: %v", err)
+	}
+
+	if len(gotFolders) != 1 || gotFolders[0].Title != "Reading" {
+		t.Fatalf("Decode() folders = %+v, want one folder titled Reading", gotFolders)
+	}
+	readingID := gotFolders[0].ID
+
+	if len(gotBookmarks) != 2 {
+		t.Fatalf("Decode() returned %d bookmarks, want 2", len(gotBookmarks))
+	}
+
+	first := gotBookmarks[0]
+	if first.URL != "https://example.com" || first.Title != "Example" || first.Excerpt != "an excerpt" {
+		t.Errorf("Decode() first bookmark = %+v", first)
+	}
+	if len(first.Tags) != 2 || first.Tags[0] != "go" || first.Tags[1] != "test" {
+		t.Errorf("Decode() first bookmark tags = %v, want [go test]", first.Tags)
+	}
+	if !first.Created.Equal(bookmarks[0].Created) {
+		t.Errorf("Decode() first bookmark Created = %v, want %v", first.Created, bookmarks[0].Created)
+	}
+	if first.FolderID != readingID {
+		t.Errorf("Decode() first bookmark FolderID = %d, want %d", first.FolderID, readingID)
+	}
+
+	second := gotBookmarks[1]
+	if second.URL != "https://golang.org" || second.FolderID != 0 {
+		t.Errorf("Decode() second bookmark = %+v, want FolderID 0", second)
+	}
+}
@@ -0,0 +1,214 @@
+package bookmarkio
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// NetscapeDecoder parses a Netscape bookmarks.html document (as exported by
+// most browsers, and by NetscapeEncoder) into Bookmarks and the folder
+// hierarchy implied by its <H3>/<DL> structure.
+type NetscapeDecoder struct {
+	r io.Reader
+}
+
+// NewNetscapeDecoder returns a NetscapeDecoder that reads from r.
+func NewNetscapeDecoder(r io.Reader) *NetscapeDecoder {
+	return &NetscapeDecoder{r: r}
+}
+
+type netscapeFolderFrame struct {
+	path string
+	id   uint32
+}
+
+// Decode parses the document. Folder IDs are synthetic, assigned in
+// document order starting at 1; 0 means a bookmark wasn't inside any
+// folder. Netscape bookmark markup is notoriously loose (unclosed <DT>/<P>
+// tags), so Decode uses a raw HTML tokenizer and only reacts to the tags it
+// cares about rather than requiring well-formed HTML.
+func (d *NetscapeDecoder) Decode() ([]Bookmark, []Folder, error) {
+	registry := newFolderRegistry()
+	stack := []netscapeFolderFrame{{path: "", id: 0}}
+
+	var bookmarks []Bookmark
+	var text strings.Builder
+	var inTitle, inLink, inExcerpt bool
+	var linkAttrs map[string]string
+
+	// flushExcerpt commits the excerpt text accumulated since the last
+	// <DD> to the most recently decoded bookmark. Real-world Netscape
+	// documents (and this package's own encoder) never close <DD> with
+	// </DD>, so the excerpt has to be flushed whenever the next element
+	// starts rather than on an end tag that will never arrive.
+	flushExcerpt := func() {
+		if !inExcerpt {
+			return
+		}
+		inExcerpt = false
+		if len(bookmarks) > 0 {
+			bookmarks[len(bookmarks)-1].Excerpt = strings.TrimSpace(text.String())
+		}
+	}
+
+	z := nethtml.NewTokenizer(d.r)
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return nil, nil, err
+			}
+			flushExcerpt()
+			return bookmarks, registry.folders, nil
+
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "h3":
+				flushExcerpt()
+				inTitle, text = true, strings.Builder{}
+			case "a":
+				flushExcerpt()
+				inLink, text = true, strings.Builder{}
+				linkAttrs = map[string]string{}
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					linkAttrs[string(key)] = string(val)
+				}
+			case "dt":
+				flushExcerpt()
+			case "dd":
+				flushExcerpt()
+				inExcerpt, text = true, strings.Builder{}
+			}
+
+		case nethtml.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "h3":
+				inTitle = false
+				parent := stack[len(stack)-1]
+				title := text.String()
+				id := registry.ensure(parent.path, title, parent.id)
+				stack = append(stack, netscapeFolderFrame{path: folderPath(parent.path, title), id: id})
+			case "dl":
+				flushExcerpt()
+				if len(stack) > 1 {
+					stack = stack[:len(stack)-1]
+				}
+			case "a":
+				inLink = false
+				parent := stack[len(stack)-1]
+				bookmarks = append(bookmarks, Bookmark{
+					URL:      linkAttrs["href"],
+					Title:    text.String(),
+					Tags:     splitTags(linkAttrs["tags"]),
+					Created:  parseAddDate(linkAttrs["add_date"]),
+					FolderID: parent.id,
+				})
+			case "dd":
+				flushExcerpt()
+			}
+
+		case nethtml.TextToken:
+			if inTitle || inLink || inExcerpt {
+				text.Write(z.Text())
+			}
+		}
+	}
+}
+
+func folderPath(parentPath, title string) string {
+	if parentPath == "" {
+		return title
+	}
+	return parentPath + "/" + title
+}
+
+func splitTags(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	fields := strings.Split(raw, ",")
+	tags := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			tags = append(tags, f)
+		}
+	}
+	return tags
+}
+
+func parseAddDate(raw string) time.Time {
+	secs, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || secs == 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0).UTC()
+}
+
+// NetscapeEncoder writes Bookmarks and their folder hierarchy as a
+// Netscape bookmarks.html document.
+type NetscapeEncoder struct {
+	w io.Writer
+}
+
+// NewNetscapeEncoder returns a NetscapeEncoder that writes to w.
+func NewNetscapeEncoder(w io.Writer) *NetscapeEncoder {
+	return &NetscapeEncoder{w: w}
+}
+
+// Encode writes bookmarks nested under their FolderID, and folders nested
+// under their ParentID, as a Netscape bookmarks.html document.
+func (e *NetscapeEncoder) Encode(bookmarks []Bookmark, folders []Folder) error {
+	childFolders := map[uint32][]Folder{}
+	for _, f := range folders {
+		childFolders[f.ParentID] = append(childFolders[f.ParentID], f)
+	}
+	folderBookmarks := map[uint32][]Bookmark{}
+	for _, b := range bookmarks {
+		folderBookmarks[b.FolderID] = append(folderBookmarks[b.FolderID], b)
+	}
+
+	var err error
+	write := func(format string, a ...interface{}) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(e.w, format, a...)
+	}
+
+	write("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n<TITLE>Bookmarks</TITLE>\n<H1>Bookmarks</H1>\n")
+
+	var writeFolder func(id uint32, depth int)
+	writeFolder = func(id uint32, depth int) {
+		indent := strings.Repeat("    ", depth)
+		write("%s<DL><p>\n", indent)
+		for _, f := range childFolders[id] {
+			write("%s    <DT><H3>%s</H3>\n", indent, html.EscapeString(f.Title))
+			writeFolder(f.ID, depth+1)
+		}
+		for _, b := range folderBookmarks[id] {
+			addDate := int64(0)
+			if !b.Created.IsZero() {
+				addDate = b.Created.Unix()
+			}
+			write(`%s    <DT><A HREF="%s" ADD_DATE="%d" TAGS="%s">%s</A>`+"\n",
+				indent, html.EscapeString(b.URL), addDate, html.EscapeString(strings.Join(b.Tags, ",")), html.EscapeString(b.Title))
+			if b.Excerpt != "" {
+				write("%s    <DD>%s\n", indent, html.EscapeString(b.Excerpt))
+			}
+		}
+		write("%s</DL><p>\n", indent)
+	}
+	writeFolder(0, 0)
+
+	return err
+}
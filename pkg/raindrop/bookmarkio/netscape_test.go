@@ -0,0 +1,77 @@
+package bookmarkio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNetscapeRoundTrip(t *testing.T) {
+	bookmarks := []Bookmark{
+		{URL: "https://example.com", Title: "Example", Excerpt: "an excerpt", Tags: []string{"go", "test"}, Created: time.Unix(1700000000, 0).UTC(), FolderID: 1},
+		{URL: "https://golang.org", Title: "Go", FolderID: 0},
+	}
+	folders := []Folder{
+		{ID: 1, ParentID: 0, Title: "Programming"},
+	}
+
+	var buf bytes.Buffer
+	if err := NewNetscapeEncoder(&buf).Encode(bookmarks, folders); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	gotBookmarks, gotFolders, err := NewNetscapeDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode() returned error: %v", err)
+	}
+
+	if len(gotFolders) != 1 || gotFolders[0].Title != "Programming" {
+		t.Fatalf("Decode() folders = %+v, want one folder titled Programming", gotFolders)
+	}
+	programmingID := gotFolders[0].ID
+
+	if len(gotBookmarks) != 2 {
+		t.Fatalf("Decode() returned %d bookmarks, want 2", len(gotBookmarks))
+	}
+
+	first := gotBookmarks[0]
+	if first.URL != "https://example.com" || first.Title != "Example" || first.Excerpt != "an excerpt" {
+		t.Errorf("Decode() first bookmark = %+v", first)
+	}
+	if len(first.Tags) != 2 || first.Tags[0] != "go" || first.Tags[1] != "test" {
+		t.Errorf("Decode() first bookmark tags = %v, want [go test]", first.Tags)
+	}
+	if !first.Created.Equal(bookmarks[0].Created) {
+		t.Errorf("Decode() first bookmark Created = %v, want %v", first.Created, bookmarks[0].Created)
+	}
+	if first.FolderID != programmingID {
+		t.Errorf("Decode() first bookmark FolderID = %d, want %d", first.FolderID, programmingID)
+	}
+
+	second := gotBookmarks[1]
+	if second.URL != "https://golang.org" || second.FolderID != 0 {
+		t.Errorf("Decode() second bookmark = %+v, want FolderID 0", second)
+	}
+	if !second.Created.IsZero() {
+		t.Errorf("Decode() second bookmark Created = %v, want zero value", second.Created)
+	}
+}
+
+func TestNetscapeEncoderZeroCreatedWritesZeroAddDate(t *testing.T) {
+	bookmarks := []Bookmark{
+		{URL: "https://example.com", Title: "No Created"},
+	}
+
+	var buf bytes.Buffer
+	if err := NewNetscapeEncoder(&buf).Encode(bookmarks, nil); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "-62135596800") {
+		t.Errorf("Encode() wrote zero-value Unix timestamp: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `ADD_DATE="0"`) {
+		t.Errorf("Encode() = %s, want ADD_DATE=\"0\" for zero-value Created", buf.String())
+	}
+}
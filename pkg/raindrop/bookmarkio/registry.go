@@ -0,0 +1,32 @@
+package bookmarkio
+
+// folderRegistry assigns synthetic, sequential Folder IDs to folder paths
+// encountered while decoding, so a Bookmark and its parent folder can
+// reference each other before either exists on the server.
+type folderRegistry struct {
+	byPath  map[string]uint32
+	folders []Folder
+	next    uint32
+}
+
+func newFolderRegistry() *folderRegistry {
+	return &folderRegistry{byPath: map[string]uint32{}, next: 1}
+}
+
+// ensure returns the synthetic ID for the folder named title under
+// parentPath (whose ID is parentID), creating it on first use.
+func (r *folderRegistry) ensure(parentPath, title string, parentID uint32) uint32 {
+	path := title
+	if parentPath != "" {
+		path = parentPath + "/" + title
+	}
+	if id, ok := r.byPath[path]; ok {
+		return id
+	}
+
+	id := r.next
+	r.next++
+	r.byPath[path] = id
+	r.folders = append(r.folders, Folder{ID: id, ParentID: parentID, Title: title})
+	return id
+}
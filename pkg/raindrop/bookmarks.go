@@ -0,0 +1,237 @@
+package raindrop
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cdzombak/raindrop-io-api-client/pkg/raindrop/bookmarkio"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ImportBookmarks decodes bookmarks from r in the given format, creates any
+// collections they reference that don't already exist (nested under
+// parentID, or as root collections if parentID is 0), and bulk-uploads the
+// resulting raindrops in batches of 100. It returns the number of raindrops
+// uploaded.
+//
+// Importing the same file twice (or two files that share folders) reuses
+// the collections created by the first import instead of duplicating them.
+//
+// The Client must have been created with NewClientWithTokenSource.
+func (c *Client) ImportBookmarks(ctx context.Context, r io.Reader, format bookmarkio.Format, parentID uint32) (int, error) {
+	dec, err := bookmarkDecoder(r, format)
+	if err != nil {
+		return 0, err
+	}
+
+	bookmarks, folders, err := dec.Decode()
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := c.token()
+	if err != nil {
+		return 0, err
+	}
+
+	existing, err := c.collectionsByParentAndTitle(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	// folders is in document order, so a folder's parent always appears
+	// before it and collectionID[f.ParentID] is already populated.
+	collectionID := map[uint32]uint32{0: parentID}
+	for _, f := range folders {
+		parent := collectionID[f.ParentID]
+		if id, ok := existing[collectionKey{parentID: parent, title: f.Title}]; ok {
+			collectionID[f.ID] = id
+			continue
+		}
+		resp, err := c.CreateCollection(ctx, token, parent == 0, "", f.Title, 0, false, parent, nil)
+		if err != nil {
+			return 0, errors.Wrapf(err, "raindrop: creating collection %q", f.Title)
+		}
+		collectionID[f.ID] = resp.Item.ID
+		existing[collectionKey{parentID: parent, title: f.Title}] = resp.Item.ID
+	}
+
+	raindrops := make([]Raindrop, len(bookmarks))
+	for i, b := range bookmarks {
+		raindrops[i] = Raindrop{
+			PleaseParse: pleaseParse{},
+			Link:        b.URL,
+			Title:       b.Title,
+			Excerpt:     b.Excerpt,
+			Tags:        b.Tags,
+			Collection:  Collection{ID: collectionID[b.FolderID]},
+		}
+		if !b.Created.IsZero() {
+			raindrops[i].Created = b.Created.Format(time.RFC3339)
+		}
+	}
+
+	uploaded := 0
+	for len(raindrops) > 0 {
+		batch := raindrops
+		if len(batch) > 100 {
+			batch = raindrops[:100]
+		}
+		if _, err := c.CreateRaindrops(ctx, token, batch); err != nil {
+			return uploaded, err
+		}
+		uploaded += len(batch)
+		raindrops = raindrops[len(batch):]
+	}
+
+	return uploaded, nil
+}
+
+// ExportBookmarks walks collectionID's child collections (every collection,
+// if collectionID is 0) and every raindrop within it, writing them to w in
+// the given format.
+//
+// The Client must have been created with NewClientWithTokenSource.
+func (c *Client) ExportBookmarks(ctx context.Context, w io.Writer, format bookmarkio.Format, collectionID uint32) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+
+	enc, err := bookmarkEncoder(w, format)
+	if err != nil {
+		return err
+	}
+
+	collections, err := c.allCollections(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	var folders []bookmarkio.Folder
+	if collectionID == 0 {
+		for _, col := range collections {
+			folders = append(folders, bookmarkio.Folder{ID: col.ID, ParentID: uint32(col.ParentId), Title: col.Title})
+		}
+	} else {
+		// The iterator below, with NestedTags set, returns raindrops
+		// from collectionID's descendants too, so the exported folder tree
+		// needs to include collectionID itself (reparented to the export's
+		// root) and every descendant, not just collectionID's own entry.
+		for _, col := range descendantCollections(collectionID, collections) {
+			parent := uint32(col.ParentId)
+			if col.ID == collectionID {
+				parent = 0
+			}
+			folders = append(folders, bookmarkio.Folder{ID: col.ID, ParentID: parent, Title: col.Title})
+		}
+	}
+
+	var bookmarks []bookmarkio.Bookmark
+	it := NewRaindropIterator(c, token, strconv.FormatUint(uint64(collectionID), 10), ListOptions{NestedTags: true})
+	for it.Next(ctx) {
+		r := it.Raindrop()
+		created, _ := time.Parse(time.RFC3339, r.Created)
+		bookmarks = append(bookmarks, bookmarkio.Bookmark{
+			URL:      r.Link,
+			Title:    r.Title,
+			Excerpt:  r.Excerpt,
+			Tags:     r.Tags,
+			Created:  created,
+			FolderID: r.Collection.ID,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	return enc.Encode(bookmarks, folders)
+}
+
+// descendantCollections returns rootID's collection and every collection
+// nested under it (directly or transitively), from collections.
+func descendantCollections(rootID uint32, collections []Collection) []Collection {
+	byParent := make(map[uint32][]Collection, len(collections))
+	byID := make(map[uint32]Collection, len(collections))
+	for _, col := range collections {
+		byParent[uint32(col.ParentId)] = append(byParent[uint32(col.ParentId)], col)
+		byID[col.ID] = col
+	}
+
+	root, ok := byID[rootID]
+	if !ok {
+		return nil
+	}
+
+	result := []Collection{root}
+	queue := []uint32{rootID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range byParent[id] {
+			result = append(result, child)
+			queue = append(queue, child.ID)
+		}
+	}
+	return result
+}
+
+// allCollections returns every root and child collection the token can see.
+func (c *Client) allCollections(ctx context.Context, token string) ([]Collection, error) {
+	root, err := c.GetRootCollections(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	children, err := c.GetChildCollections(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return append(root.Items, children.Items...), nil
+}
+
+// collectionKey identifies a collection by its parent and title, which is
+// how ImportBookmarks matches a folder against a collection that already
+// exists.
+type collectionKey struct {
+	parentID uint32
+	title    string
+}
+
+// collectionsByParentAndTitle indexes every collection the token can see by
+// collectionKey, so ImportBookmarks can skip creating collections that
+// already exist.
+func (c *Client) collectionsByParentAndTitle(ctx context.Context, token string) (map[collectionKey]uint32, error) {
+	collections, err := c.allCollections(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[collectionKey]uint32, len(collections))
+	for _, col := range collections {
+		byKey[collectionKey{parentID: uint32(col.ParentId), title: col.Title}] = col.ID
+	}
+	return byKey, nil
+}
+
+func bookmarkDecoder(r io.Reader, format bookmarkio.Format) (bookmarkio.Decoder, error) {
+	switch format {
+	case bookmarkio.FormatNetscape:
+		return bookmarkio.NewNetscapeDecoder(r), nil
+	case bookmarkio.FormatCSV:
+		return bookmarkio.NewCSVDecoder(r), nil
+	default:
+		return nil, errors.New("raindrop: unknown bookmark format")
+	}
+}
+
+func bookmarkEncoder(w io.Writer, format bookmarkio.Format) (bookmarkio.Encoder, error) {
+	switch format {
+	case bookmarkio.FormatNetscape:
+		return bookmarkio.NewNetscapeEncoder(w), nil
+	case bookmarkio.FormatCSV:
+		return bookmarkio.NewCSVEncoder(w), nil
+	default:
+		return nil, errors.New("raindrop: unknown bookmark format")
+	}
+}
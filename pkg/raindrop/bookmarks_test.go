@@ -0,0 +1,180 @@
+package raindrop
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cdzombak/raindrop-io-api-client/pkg/raindrop/bookmarkio"
+	"golang.org/x/net/context"
+)
+
+// collectionServer is a minimal stateful fake of the collections and
+// raindrops endpoints ImportBookmarks/ExportBookmarks depend on, so their
+// collection-dedup and folder-reparenting logic can be exercised without a
+// real Raindrop.io account.
+type collectionServer struct {
+	mu          sync.Mutex
+	collections []Collection
+	nextID      uint32
+	creates     int
+	uploaded    []Raindrop
+	raindrops   []Raindrop
+}
+
+func (s *collectionServer) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v1/collections":
+			var items []Collection
+			for _, c := range s.collections {
+				if c.ParentId == 0 {
+					items = append(items, c)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(GetCollectionsResponse{Result: true, Items: items})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v1/collections/childrens":
+			var items []Collection
+			for _, c := range s.collections {
+				if c.ParentId != 0 {
+					items = append(items, c)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(GetCollectionsResponse{Result: true, Items: items})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/v1/collection":
+			var req createCollectionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.creates++
+			s.nextID++
+			col := Collection{ID: s.nextID, Title: req.Title, ParentId: int(req.ParentId)}
+			s.collections = append(s.collections, col)
+			_ = json.NewEncoder(w).Encode(CreateCollectionResponse{Result: true, Item: col})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/v1/raindrops":
+			var req createRaindropsRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.uploaded = append(s.uploaded, req.Items...)
+			_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: req.Items})
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/rest/v1/raindrops/"):
+			_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: s.raindrops})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func netscapeBookmarks(t *testing.T, bookmarks []bookmarkio.Bookmark, folders []bookmarkio.Folder) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := bookmarkio.NewNetscapeEncoder(&buf).Encode(bookmarks, folders); err != nil {
+		t.Fatalf("encoding test fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportBookmarksReusesExistingCollections(t *testing.T) {
+	server := &collectionServer{}
+	httpServer := httptest.NewServer(server.handler())
+	defer httpServer.Close()
+
+	client, err := NewClientWithTokenSource("id", "secret", "redirect", StaticTokenSource(&Token{AccessToken: "a-token", Expiry: time.Now().Add(time.Hour)}), WithBaseURL(httpServer.URL))
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource() returned error: %v", err)
+	}
+
+	fixture := netscapeBookmarks(t,
+		[]bookmarkio.Bookmark{{URL: "https://example.com", Title: "Example", FolderID: 1}},
+		[]bookmarkio.Folder{{ID: 1, ParentID: 0, Title: "Programming"}},
+	)
+
+	n, err := client.ImportBookmarks(context.Background(), bytes.NewReader(fixture), bookmarkio.FormatNetscape, 0)
+	if err != nil {
+		t.Fatalf("ImportBookmarks() (first import) returned error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("ImportBookmarks() (first import) uploaded %d raindrops, want 1", n)
+	}
+	if server.creates != 1 {
+		t.Fatalf("after first import, created %d collections, want 1", server.creates)
+	}
+
+	n, err = client.ImportBookmarks(context.Background(), bytes.NewReader(fixture), bookmarkio.FormatNetscape, 0)
+	if err != nil {
+		t.Fatalf("ImportBookmarks() (second import) returned error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("ImportBookmarks() (second import) uploaded %d raindrops, want 1", n)
+	}
+	if server.creates != 1 {
+		t.Errorf("after second import, created %d collections, want still 1 (existing collection should be reused)", server.creates)
+	}
+
+	if len(server.uploaded) != 2 {
+		t.Fatalf("uploaded %d raindrops total, want 2", len(server.uploaded))
+	}
+	for _, r := range server.uploaded {
+		if r.Collection.ID != server.collections[0].ID {
+			t.Errorf("uploaded raindrop Collection.ID = %d, want %d (the reused collection)", r.Collection.ID, server.collections[0].ID)
+		}
+	}
+}
+
+func TestExportBookmarksNonRootCollection(t *testing.T) {
+	server := &collectionServer{
+		collections: []Collection{
+			{ID: 1, Title: "Programming", ParentId: 0},
+			{ID: 2, Title: "Go", ParentId: 1},
+		},
+		nextID: 2,
+		raindrops: []Raindrop{
+			{Link: "https://golang.org", Title: "Go", Collection: Collection{ID: 2}},
+		},
+	}
+	httpServer := httptest.NewServer(server.handler())
+	defer httpServer.Close()
+
+	client, err := NewClientWithTokenSource("id", "secret", "redirect", StaticTokenSource(&Token{AccessToken: "a-token", Expiry: time.Now().Add(time.Hour)}), WithBaseURL(httpServer.URL))
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource() returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := client.ExportBookmarks(context.Background(), &buf, bookmarkio.FormatNetscape, 2); err != nil {
+		t.Fatalf("ExportBookmarks() returned error: %v", err)
+	}
+
+	bookmarks, folders, err := bookmarkio.NewNetscapeDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("decoding exported bookmarks: %v", err)
+	}
+
+	// Collection 2 ("Go") is the export root, so it must be reparented to
+	// the top level even though raindrop-io still considers it nested
+	// under collection 1 ("Programming").
+	if len(folders) != 1 || folders[0].Title != "Go" || folders[0].ParentID != 0 {
+		t.Fatalf("Decode() folders = %+v, want one top-level folder titled Go", folders)
+	}
+	if len(bookmarks) != 1 || bookmarks[0].URL != "https://golang.org" || bookmarks[0].FolderID != folders[0].ID {
+		t.Errorf("Decode() bookmarks = %+v, want the golang.org bookmark inside the Go folder", bookmarks)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strconv"
 	"time"
@@ -34,10 +35,16 @@ const (
 	endpointRaindrop  = "/rest/v1/raindrop"
 	endpointRaindrops = "/rest/v1/raindrops/"
 	endpointTags      = "/rest/v1/tags"
-
-	defaultTimeout = 5 * time.Second
 )
 
+// Logger receives diagnostic messages the Client would otherwise write to
+// stderr, e.g. an invalid WithBaseURL URL or a failed write in
+// GetAuthorizationCodeHandler. Messages about unexpected response status
+// codes, and FetchTitle's messages, are still written to stderr.
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
 // Client is a raindrop client
 type Client struct {
 	apiURL       *url.URL
@@ -47,6 +54,26 @@ type Client struct {
 	clientSecret string
 	redirectUri  string
 	ClientCode   string
+	tokenSource  TokenSource
+	timeout      time.Duration
+	userAgent    string
+	logger       Logger
+}
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Logf(format, args...)
+		return
+	}
+	ePrintf(format, args...)
+}
+
+func (c *Client) logln(s string) {
+	if c.logger != nil {
+		c.logger.Logf("%s", s)
+		return
+	}
+	ePrintln(s)
 }
 
 // AccessTokenResponse represents the token exchange api response item
@@ -88,10 +115,10 @@ type createCollectionRequest struct {
 
 // CreateCollectionResponse represents create collection api response item
 type CreateCollectionResponse struct {
-	Result       bool                    `json:"result"`
-	Item         createCollectionRequest `json:"item,omitempty"`
-	Error        string                  `json:"error,omitempty"`
-	ErrorMessage string                  `json:"errorMessage,omitempty"`
+	Result       bool       `json:"result"`
+	Item         Collection `json:"item,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	ErrorMessage string     `json:"errorMessage,omitempty"`
 }
 
 type deleteTagsRequest struct {
@@ -191,8 +218,87 @@ type Tags struct {
 	Items  []Tag `json:"items"`
 }
 
+// ClientOption customizes a Client returned by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient overrides the http.Client used for every API request,
+// including its Transport. Use this if you need full control over the
+// transport instead of layering onto the default RetryTransport.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetryPolicy replaces the backoff policy used by the Client's default
+// RetryTransport. It has no effect if WithHTTPClient was also given, since
+// that option takes the transport out of the Client's hands entirely.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if rt, ok := c.httpClient.Transport.(*RetryTransport); ok {
+			rt.Policy = p
+		}
+	}
+}
+
+// WithRateLimiter plugs a proactive rate limiter (e.g.
+// golang.org/x/time/rate.Limiter, which already satisfies RateLimiter) into
+// the Client's default RetryTransport, so requests are throttled before
+// they're sent rather than retried after a 429. It has no effect if
+// WithHTTPClient was also given.
+func WithRateLimiter(rl RateLimiter) ClientOption {
+	return func(c *Client) {
+		if rt, ok := c.httpClient.Transport.(*RetryTransport); ok {
+			rt.RateLimiter = rl
+		}
+	}
+}
+
+// WithTimeout sets a default per-request timeout, applied via
+// context.WithTimeout to any call whose context.Context has no deadline of
+// its own. Unlike the old hard-coded http.Client.Timeout, it never
+// overrides a deadline the caller already set, and callers that pass no
+// timeout at all get none — appropriate for e.g. a large ImportBookmarks
+// call that shouldn't be cut off after a few seconds.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithBaseURL overrides the Raindrop.io API's base URL, e.g. to point at a
+// staging environment or a mock server in tests.
+func WithBaseURL(rawURL string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			c.logf("WithBaseURL: invalid URL %q: %v\n", rawURL, err)
+			return
+		}
+		c.apiURL = u
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithLogger routes the Client's diagnostic messages (errors it can't
+// return, like a failed save of a refreshed token) to logger instead of
+// stderr. Messages about unexpected response status codes still go to
+// stderr.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) { c.logger = logger }
+}
+
+// WithTransport replaces the Client's http.RoundTripper outright — e.g. to
+// drop the default RetryTransport in favor of
+// github.com/hashicorp/go-retryablehttp, or to layer both together. Unlike
+// WithHTTPClient, it leaves the rest of the http.Client (like WithTimeout's
+// interaction with per-call contexts) untouched.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) { c.httpClient.Transport = rt }
+}
+
 // NewClient creates Raindrop.io client
-func NewClient(clientId string, clientSecret string, redirectUri string) (*Client, error) {
+func NewClient(clientId string, clientSecret string, redirectUri string, opts ...ClientOption) (*Client, error) {
 	auth, err := url.Parse(authHost)
 	if err != nil {
 		return nil, err
@@ -211,29 +317,68 @@ func NewClient(clientId string, clientSecret string, redirectUri string) (*Clien
 		apiURL:  api,
 		authURL: auth,
 		httpClient: &http.Client{
-			Timeout:   defaultTimeout,
-			Transport: tr,
+			Transport: NewRetryTransport(tr, DefaultRetryPolicy()),
 		},
 		clientId:     clientId,
 		clientSecret: clientSecret,
 		redirectUri:  redirectUri,
 	}
 
+	for _, opt := range opts {
+		opt(&client)
+	}
+
 	return &client, nil
 }
 
+// boundContext returns ctx (context.Background() if nil), wrapped in
+// context.WithTimeout against the Client's WithTimeout duration if one was
+// set and ctx doesn't already carry its own deadline. The returned
+// CancelFunc is intentionally not required to be called: on timeout the
+// context cancels itself, following the same single-cancel-channel pattern
+// as context.WithTimeout itself, just scoped to one request instead of a
+// whole http.Client.
+func (c *Client) boundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// execute sends req using the Client's http.Client, unwrapping a
+// *RateLimitError from the underlying RetryTransport (net/http otherwise
+// buries it inside a *url.Error) so callers can type-assert for it directly.
+func (c *Client) execute(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ue, ok := err.(*url.Error); ok {
+			if rlErr, ok := ue.Err.(*RateLimitError); ok {
+				return nil, rlErr
+			}
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetRootCollections call Get root collections API.
 // Reference: https://developer.raindrop.io/v1/collections/methods#get-root-collections
-func (c *Client) GetRootCollections(accessToken string, ctx context.Context) (*GetCollectionsResponse, error) {
+func (c *Client) GetRootCollections(ctx context.Context, accessToken string) (*GetCollectionsResponse, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointGetRootCollections)
 
-	req, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	req, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(req)
+	response, err := c.execute(req)
 	if err != nil {
 		return nil, err
 	}
@@ -246,18 +391,27 @@ func (c *Client) GetRootCollections(accessToken string, ctx context.Context) (*G
 	return r, nil
 }
 
+// GetRootCollectionsLegacy is GetRootCollections with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use GetRootCollections, or GetRootCollectionsV2 with a Client
+// created via NewClientWithTokenSource.
+func (c *Client) GetRootCollectionsLegacy(accessToken string, ctx context.Context) (*GetCollectionsResponse, error) {
+	return c.GetRootCollections(ctx, accessToken)
+}
+
 // GetChildCollections call Get child collections API.
 // Reference: https://developer.raindrop.io/v1/collections/methods#get-child-collections
-func (c *Client) GetChildCollections(accessToken string, ctx context.Context) (*GetCollectionsResponse, error) {
+func (c *Client) GetChildCollections(ctx context.Context, accessToken string) (*GetCollectionsResponse, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointGetChildCollections)
 
-	req, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	req, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.execute(req)
 	if err != nil {
 		return nil, err
 	}
@@ -270,18 +424,27 @@ func (c *Client) GetChildCollections(accessToken string, ctx context.Context) (*
 	return result, nil
 }
 
+// GetChildCollectionsLegacy is GetChildCollections with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use GetChildCollections, or GetChildCollectionsV2 with a
+// Client created via NewClientWithTokenSource.
+func (c *Client) GetChildCollectionsLegacy(accessToken string, ctx context.Context) (*GetCollectionsResponse, error) {
+	return c.GetChildCollections(ctx, accessToken)
+}
+
 // GetCollection call Get collection API.
 // Reference: https://developer.raindrop.io/v1/collections/methods#get-collection
-func (c Client) GetCollection(accessToken string, id uint32, ctx context.Context) (*GetCollectionResponse, error) {
+func (c *Client) GetCollection(ctx context.Context, accessToken string, id uint32) (*GetCollectionResponse, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointGetCollection+strconv.Itoa(int(id)))
 
-	req, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	req, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.execute(req)
 	if err != nil {
 		return nil, err
 	}
@@ -294,10 +457,19 @@ func (c Client) GetCollection(accessToken string, id uint32, ctx context.Context
 	return result, nil
 }
 
+// GetCollectionLegacy is GetCollection with its pre-ctx-first parameter
+// order.
+//
+// Deprecated: use GetCollection, or GetCollectionV2 with a Client created
+// via NewClientWithTokenSource.
+func (c Client) GetCollectionLegacy(accessToken string, id uint32, ctx context.Context) (*GetCollectionResponse, error) {
+	return c.GetCollection(ctx, accessToken, id)
+}
+
 // CreateCollection creates new Collection
 // Reference: https://developer.raindrop.io/v1/collections/methods#create-collection
-func (c *Client) CreateCollection(accessToken string, isRoot bool, view string, title string, sort int,
-	public bool, parentId uint32, cover []string, ctx context.Context) (*CreateCollectionResponse, error) {
+func (c *Client) CreateCollection(ctx context.Context, accessToken string, isRoot bool, view string, title string,
+	sort int, public bool, parentId uint32, cover []string) (*CreateCollectionResponse, error) {
 
 	fullUrl := *c.apiURL
 	fullUrl.Path = path.Join(endpointCreateCollection)
@@ -323,12 +495,12 @@ func (c *Client) CreateCollection(accessToken string, isRoot bool, view string,
 		}
 	}
 
-	request, err := c.newRequest(accessToken, http.MethodPost, fullUrl, collection, ctx)
+	request, err := c.newRequest(ctx, accessToken, http.MethodPost, fullUrl, collection)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -342,39 +514,38 @@ func (c *Client) CreateCollection(accessToken string, isRoot bool, view string,
 	return result, nil
 }
 
-// CreateSimpleRaindrop creates new simple unsorted Raindrop
+// CreateCollectionLegacy is CreateCollection with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use CreateCollection, or CreateCollectionV2 with a Client
+// created via NewClientWithTokenSource.
+func (c *Client) CreateCollectionLegacy(accessToken string, isRoot bool, view string, title string, sort int,
+	public bool, parentId uint32, cover []string, ctx context.Context) (*CreateCollectionResponse, error) {
+	return c.CreateCollection(ctx, accessToken, isRoot, view, title, sort, public, parentId, cover)
+}
+
+// CreateSimpleRaindrop creates new simple unsorted Raindrop, relying on the
+// Raindrop.io API's own pleaseParse handling to fill in the title and
+// preview rather than fetching the page client-side. Use FetchTitle (or a
+// custom TitlePrefetcher) beforehand if you need the title before the API
+// call returns.
+//
 // Reference: https://developer.raindrop.io/v1/raindrops/single#create-raindrop
-func (c *Client) CreateSimpleRaindrop(accessToken string, link string, ctx context.Context) (*SingleRaindropResponse, error) {
+func (c *Client) CreateSimpleRaindrop(ctx context.Context, accessToken string, link string) (*SingleRaindropResponse, error) {
 	fullUrl := *c.apiURL
 	fullUrl.Path = path.Join(endpointRaindrop)
 
-	resp, _ := http.Get(link)
-	defer func() {
-		err := resp.Body.Close()
-		if err != nil {
-			ePrintf("Can't close response's Body in CreateSimpleRaindrop: %v\n", err)
-		}
-	}()
-
-	title := ""
-	if val, ok := GetHtmlTitle(resp.Body); ok {
-		title = val
-	} else {
-		title = "Fail to get HTML title"
-	}
-
 	raindrop := Raindrop{
 		PleaseParse: pleaseParse{},
-		Title:       title,
 		Link:        link,
 	}
 
-	request, err := c.newRequest(accessToken, http.MethodPost, fullUrl, raindrop, ctx)
+	request, err := c.newRequest(ctx, accessToken, http.MethodPost, fullUrl, raindrop)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -388,13 +559,25 @@ func (c *Client) CreateSimpleRaindrop(accessToken string, link string, ctx conte
 	return result, nil
 }
 
+// CreateSimpleRaindropLegacy is CreateSimpleRaindrop with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use CreateSimpleRaindrop, or CreateSimpleRaindropV2 with a
+// Client created via NewClientWithTokenSource.
+func (c *Client) CreateSimpleRaindropLegacy(accessToken string, link string, ctx context.Context) (*SingleRaindropResponse, error) {
+	return c.CreateSimpleRaindrop(ctx, accessToken, link)
+}
+
 // GetRaindrops call get raindrops API.
 // Reference: https://developer.raindrop.io/v1/raindrops/multiple#get-raindrops
-func (c *Client) GetRaindrops(accessToken string, collectionID string, perpage int, ctx context.Context) (*MultiRaindropsResponse, error) {
+//
+// Deprecated: use ListRaindrops, which exposes paging, sorting and search,
+// or NewRaindropIterator to page through all results automatically.
+func (c *Client) GetRaindrops(ctx context.Context, accessToken string, collectionID string, perpage int) (*MultiRaindropsResponse, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointRaindrops, collectionID)
 
-	req, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	req, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +586,7 @@ func (c *Client) GetRaindrops(accessToken string, collectionID string, perpage i
 	query.Add("perpage", fmt.Sprint(perpage))
 	req.URL.RawQuery = query.Encode()
 
-	response, err := c.httpClient.Do(req)
+	response, err := c.execute(req)
 	if err != nil {
 		return nil, err
 	}
@@ -416,17 +599,25 @@ func (c *Client) GetRaindrops(accessToken string, collectionID string, perpage i
 	return r, nil
 }
 
+// GetRaindropsLegacy is GetRaindrops with its pre-ctx-first parameter
+// order.
+//
+// Deprecated: use GetRaindrops, ListRaindrops, or NewRaindropIterator.
+func (c *Client) GetRaindropsLegacy(accessToken string, collectionID string, perpage int, ctx context.Context) (*MultiRaindropsResponse, error) {
+	return c.GetRaindrops(ctx, accessToken, collectionID, perpage)
+}
+
 // GetTags calls Get tags API.
 // Reference: https://developer.raindrop.io/v1/tags#get-tags
-func (c *Client) GetTags(accessToken string, ctx context.Context) (*Tags, error) {
+func (c *Client) GetTags(ctx context.Context, accessToken string) (*Tags, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointTags)
-	request, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	request, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -439,18 +630,26 @@ func (c *Client) GetTags(accessToken string, ctx context.Context) (*Tags, error)
 	return r, nil
 }
 
+// GetTagsLegacy is GetTags with its pre-ctx-first parameter order.
+//
+// Deprecated: use GetTags, or GetTagsV2 with a Client created via
+// NewClientWithTokenSource.
+func (c *Client) GetTagsLegacy(accessToken string, ctx context.Context) (*Tags, error) {
+	return c.GetTags(ctx, accessToken)
+}
+
 // DeleteTags calls Delete tags API.
 // Reference: https://developer.raindrop.io/v1/tags#remove-tag-s
-func (c *Client) DeleteTags(accessToken string, ctx context.Context, tagIDs []string) error {
+func (c *Client) DeleteTags(ctx context.Context, accessToken string, tagIDs []string) error {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointTags)
 	body := deleteTagsRequest{Tags: tagIDs}
-	request, err := c.newRequest(accessToken, http.MethodDelete, u, body, ctx)
+	request, err := c.newRequest(ctx, accessToken, http.MethodDelete, u, body)
 	if err != nil {
 		return err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return err
 	}
@@ -463,24 +662,35 @@ func (c *Client) DeleteTags(accessToken string, ctx context.Context, tagIDs []st
 	return nil
 }
 
+// DeleteTagsLegacy is DeleteTags with its pre-ctx-first parameter order.
+//
+// Deprecated: use DeleteTags, or DeleteTagsV2 with a Client created via
+// NewClientWithTokenSource.
+func (c *Client) DeleteTagsLegacy(accessToken string, ctx context.Context, tagIDs []string) error {
+	return c.DeleteTags(ctx, accessToken, tagIDs)
+}
+
 // GetTaggedRaindrops finds raindrops with exact tags.
 // This function calls Get raindrops API with collectionID=0 and specify given tag as a search parameter.
 //
 // Reference: https://developer.raindrop.io/v1/raindrops/multiple#search-parameter
-func (c *Client) GetTaggedRaindrops(accessToken string, tag string, ctx context.Context) (*MultiRaindropsResponse, error) {
+func (c *Client) GetTaggedRaindrops(ctx context.Context, accessToken string, tag string) (*MultiRaindropsResponse, error) {
 	u := *c.apiURL
 	u.Path = path.Join(c.apiURL.Path, endpointRaindrops+"0")
-	request, err := c.newRequest(accessToken, http.MethodGet, u, nil, ctx)
+	request, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	searchJSON, err := new(SearchQuery).Tag(tag).Marshal()
+	if err != nil {
+		return nil, err
+	}
 	params := request.URL.Query()
-	searchParameter := createSingleSearchParameter("tag", tag)
-	params.Add("search", searchParameter)
+	params.Add("search", searchJSON)
 	request.URL.RawQuery = params.Encode()
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -493,6 +703,15 @@ func (c *Client) GetTaggedRaindrops(accessToken string, tag string, ctx context.
 	return r, nil
 }
 
+// GetTaggedRaindropsLegacy is GetTaggedRaindrops with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use GetTaggedRaindrops, or GetTaggedRaindropsV2 with a Client
+// created via NewClientWithTokenSource.
+func (c *Client) GetTaggedRaindropsLegacy(accessToken string, tag string, ctx context.Context) (*MultiRaindropsResponse, error) {
+	return c.GetTaggedRaindrops(ctx, accessToken, tag)
+}
+
 // GetAuthorizationURL returns URL for user to authorize app
 func (c *Client) GetAuthorizationURL() (url.URL, error) {
 	u := c.authURL
@@ -503,7 +722,7 @@ func (c *Client) GetAuthorizationURL() (url.URL, error) {
 
 // GetAccessToken exchanges user's authorization code to access token
 // Reference: https://developer.raindrop.io/v1/authentication/token#step-3-the-token-exchange
-func (c *Client) GetAccessToken(userCode string, ctx context.Context) (*AccessTokenResponse, error) {
+func (c *Client) GetAccessToken(ctx context.Context, userCode string) (*AccessTokenResponse, error) {
 	fullUrl := *c.authURL
 	fullUrl.Path = path.Join(endpointAccessToken)
 
@@ -515,12 +734,12 @@ func (c *Client) GetAccessToken(userCode string, ctx context.Context) (*AccessTo
 		GrantType:    "authorization_code",
 	}
 
-	request, err := c.newRequest("", http.MethodPost, fullUrl, body, ctx)
+	request, err := c.newRequest(ctx, "", http.MethodPost, fullUrl, body)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -534,25 +753,33 @@ func (c *Client) GetAccessToken(userCode string, ctx context.Context) (*AccessTo
 	return result, nil
 }
 
+// GetAccessTokenLegacy is GetAccessToken with its pre-ctx-first parameter
+// order.
+//
+// Deprecated: use GetAccessToken.
+func (c *Client) GetAccessTokenLegacy(userCode string, ctx context.Context) (*AccessTokenResponse, error) {
+	return c.GetAccessToken(ctx, userCode)
+}
+
 // RefreshAccessToken refreshes expired token
 // Reference: https://developer.raindrop.io/v1/authentication/token#the-access-token-refresh
-func (c *Client) RefreshAccessToken(refreshToken string, ctx context.Context) (*AccessTokenResponse, error) {
+func (c *Client) RefreshAccessToken(ctx context.Context, refreshToken string) (*AccessTokenResponse, error) {
 	fullUrl := *c.authURL
 	fullUrl.Path = path.Join(endpointAccessToken)
 
 	body := refreshTokenRequest{
 		ClientId:     c.clientId,
 		ClientSecret: c.clientSecret,
-		GrantType:    "authorization_code",
+		GrantType:    "refresh_token",
 		RefreshToken: refreshToken,
 	}
 
-	request, err := c.newRequest("", http.MethodPost, fullUrl, body, ctx)
+	request, err := c.newRequest(ctx, "", http.MethodPost, fullUrl, body)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.httpClient.Do(request)
+	response, err := c.execute(request)
 	if err != nil {
 		return nil, err
 	}
@@ -565,6 +792,14 @@ func (c *Client) RefreshAccessToken(refreshToken string, ctx context.Context) (*
 	return result, nil
 }
 
+// RefreshAccessTokenLegacy is RefreshAccessToken with its pre-ctx-first
+// parameter order.
+//
+// Deprecated: use RefreshAccessToken.
+func (c *Client) RefreshAccessTokenLegacy(refreshToken string, ctx context.Context) (*AccessTokenResponse, error) {
+	return c.RefreshAccessToken(ctx, refreshToken)
+}
+
 // GetAuthorizationCodeHandler handles redirect request from raindrop's authorization page
 //
 //goland:noinspection GoMixedReceiverTypes
@@ -573,12 +808,12 @@ func (c *Client) GetAuthorizationCodeHandler(w http.ResponseWriter, r *http.Requ
 
 	code, err := c.GetAuthorizationCode(r)
 	if err != nil {
-		ePrintln(err.Error())
+		c.logln(err.Error())
 	}
 
 	_, err = fmt.Fprintf(w, "<h1>You've been authorized</h1><p>%s</p>", code)
 	if err != nil {
-		ePrintln(err.Error())
+		c.logln(err.Error())
 	}
 	c.ClientCode = code
 }
@@ -598,12 +833,8 @@ func (c *Client) GetAuthorizationCode(r *http.Request) (string, error) {
 	return code, nil
 }
 
-func createSingleSearchParameter(k, v string) string {
-	return fmt.Sprintf(`[{"key":"%s","val":"%s"}]`, k, v)
-}
-
-func (c *Client) newRequest(accessToken string, httpMethod string, fullUrl url.URL,
-	body interface{}, ctx context.Context) (*http.Request, error) {
+func (c *Client) newRequest(ctx context.Context, accessToken string, httpMethod string, fullUrl url.URL,
+	body interface{}) (*http.Request, error) {
 
 	u, err := url.QueryUnescape(fullUrl.String())
 	if err != nil {
@@ -618,20 +849,16 @@ func (c *Client) newRequest(accessToken string, httpMethod string, fullUrl url.U
 		}
 	}
 
-	var req *http.Request
-	if ctx != nil {
-		req, err = http.NewRequestWithContext(ctx, httpMethod, u, &b)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		req, err = http.NewRequest(httpMethod, u, &b)
-		if err != nil {
-			return nil, err
-		}
+	boundCtx, _ := c.boundContext(ctx)
+	req, err := http.NewRequestWithContext(boundCtx, httpMethod, u, &b)
+	if err != nil {
+		return nil, err
 	}
 
 	req.Header.Add("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	if accessToken != "" {
 		bearerToken := fmt.Sprintf("Bearer %s", accessToken)
@@ -665,7 +892,7 @@ func parseResponse(response *http.Response, expectedStatus int, clazz interface{
 
 func ePrintf(f string, a ...interface{}) {
 	_, _ = fmt.Fprint(os.Stderr, "[raindrop-io-api-client] ")
-	_, _ = fmt.Fprintf(os.Stderr, f, a)
+	_, _ = fmt.Fprintf(os.Stderr, f, a...)
 }
 
 func ePrintln(s string) {
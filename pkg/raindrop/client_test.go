@@ -0,0 +1,157 @@
+package raindrop
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Logf(format string, args ...interface{}) {
+	l.messages = append(l.messages, format)
+}
+
+func TestBoundContextAppliesTimeoutWhenCtxHasNoDeadline(t *testing.T) {
+	c := &Client{timeout: time.Hour}
+
+	ctx, cancel := c.boundContext(context.Background())
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("boundContext() returned a context with no deadline, want one from WithTimeout")
+	}
+	if until := time.Until(deadline); until <= 0 || until > time.Hour {
+		t.Errorf("boundContext() deadline is %s from now, want close to 1h", until)
+	}
+}
+
+func TestBoundContextPreservesExistingDeadline(t *testing.T) {
+	c := &Client{timeout: time.Hour}
+
+	want := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+
+	got, cancel2 := c.boundContext(ctx)
+	defer cancel2()
+
+	deadline, ok := got.Deadline()
+	if !ok || !deadline.Equal(want) {
+		t.Errorf("boundContext() deadline = %v, ok=%v, want %v (the caller's own deadline, untouched)", deadline, ok, want)
+	}
+}
+
+func TestBoundContextNoTimeoutConfigured(t *testing.T) {
+	c := &Client{}
+
+	ctx, cancel := c.boundContext(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("boundContext() with no Client timeout set returned a context with a deadline")
+	}
+}
+
+func TestBoundContextNilContext(t *testing.T) {
+	c := &Client{}
+
+	ctx, cancel := c.boundContext(nil)
+	defer cancel()
+
+	if ctx == nil {
+		t.Fatal("boundContext(nil) returned a nil context")
+	}
+}
+
+func TestWithBaseURLInvalidURLLeavesApiURLUnchanged(t *testing.T) {
+	original, err := url.Parse(apiHost)
+	if err != nil {
+		t.Fatalf("url.Parse(apiHost) returned error: %v", err)
+	}
+	logger := &recordingLogger{}
+	c := &Client{apiURL: original, logger: logger}
+
+	WithBaseURL("http://foo.com/%zz")(c)
+
+	if c.apiURL != original {
+		t.Errorf("WithBaseURL with an invalid URL replaced apiURL; want it left unchanged")
+	}
+	if len(logger.messages) == 0 {
+		t.Error("WithBaseURL with an invalid URL didn't log anything")
+	}
+}
+
+func TestWithBaseURLValidURL(t *testing.T) {
+	c := &Client{}
+
+	WithBaseURL("https://staging.example.com")(c)
+
+	if c.apiURL == nil || c.apiURL.String() != "https://staging.example.com" {
+		t.Errorf("WithBaseURL() apiURL = %v, want https://staging.example.com", c.apiURL)
+	}
+}
+
+func TestExecuteUnwrapsRateLimitError(t *testing.T) {
+	want := &RateLimitError{Retries: 3, RetryAfter: time.Second}
+	c := &Client{
+		httpClient: &http.Client{
+			Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				// http.Client.Do wraps whatever the RoundTripper returns in
+				// its own *url.Error, so the RoundTripper itself should
+				// return the bare *RateLimitError, matching what
+				// RetryTransport does.
+				return nil, want
+			}),
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() returned error: %v", err)
+	}
+
+	_, err = c.execute(req)
+	got, ok := err.(*RateLimitError)
+	if !ok {
+		t.Fatalf("execute() returned error of type %T, want *RateLimitError", err)
+	}
+	if got != want {
+		t.Errorf("execute() returned %v, want the underlying %v", got, want)
+	}
+}
+
+func TestExecutePassesThroughOtherErrors(t *testing.T) {
+	c := &Client{
+		httpClient: &http.Client{
+			Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				return nil, context.DeadlineExceeded
+			}),
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() returned error: %v", err)
+	}
+
+	_, err = c.execute(req)
+	if _, ok := err.(*RateLimitError); ok {
+		t.Fatal("execute() returned a *RateLimitError for an unrelated *url.Error")
+	}
+	if err == nil {
+		t.Fatal("execute() returned a nil error")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
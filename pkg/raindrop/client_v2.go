@@ -0,0 +1,182 @@
+package raindrop
+
+import (
+	"golang.org/x/net/context"
+)
+
+// GetRootCollectionsV2 is GetRootCollections, but takes its access token
+// from the Client's TokenSource instead of a parameter. The Client must
+// have been created with NewClientWithTokenSource.
+func (c *Client) GetRootCollectionsV2(ctx context.Context) (*GetCollectionsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRootCollections(ctx, token)
+}
+
+// GetChildCollectionsV2 is GetChildCollections, but takes its access token
+// from the Client's TokenSource instead of a parameter. The Client must
+// have been created with NewClientWithTokenSource.
+func (c *Client) GetChildCollectionsV2(ctx context.Context) (*GetCollectionsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetChildCollections(ctx, token)
+}
+
+// GetCollectionV2 is GetCollection, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) GetCollectionV2(ctx context.Context, id uint32) (*GetCollectionResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCollection(ctx, token, id)
+}
+
+// CreateCollectionV2 is CreateCollection, but takes its access token from
+// the Client's TokenSource instead of a parameter. The Client must have
+// been created with NewClientWithTokenSource.
+func (c *Client) CreateCollectionV2(ctx context.Context, isRoot bool, view string, title string, sort int,
+	public bool, parentId uint32, cover []string) (*CreateCollectionResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateCollection(ctx, token, isRoot, view, title, sort, public, parentId, cover)
+}
+
+// CreateSimpleRaindropV2 is CreateSimpleRaindrop, but takes its access
+// token from the Client's TokenSource instead of a parameter. The Client
+// must have been created with NewClientWithTokenSource.
+func (c *Client) CreateSimpleRaindropV2(ctx context.Context, link string) (*SingleRaindropResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateSimpleRaindrop(ctx, token, link)
+}
+
+// GetRaindropsV2 is GetRaindrops, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) GetRaindropsV2(ctx context.Context, collectionID string, perpage int) (*MultiRaindropsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRaindrops(ctx, token, collectionID, perpage)
+}
+
+// GetTagsV2 is GetTags, but takes its access token from the Client's
+// TokenSource instead of a parameter. The Client must have been created
+// with NewClientWithTokenSource.
+func (c *Client) GetTagsV2(ctx context.Context) (*Tags, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTags(ctx, token)
+}
+
+// DeleteTagsV2 is DeleteTags, but takes its access token from the Client's
+// TokenSource instead of a parameter. The Client must have been created
+// with NewClientWithTokenSource.
+func (c *Client) DeleteTagsV2(ctx context.Context, tagIDs []string) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	return c.DeleteTags(ctx, token, tagIDs)
+}
+
+// GetTaggedRaindropsV2 is GetTaggedRaindrops, but takes its access token
+// from the Client's TokenSource instead of a parameter. The Client must
+// have been created with NewClientWithTokenSource.
+func (c *Client) GetTaggedRaindropsV2(ctx context.Context, tag string) (*MultiRaindropsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetTaggedRaindrops(ctx, token, tag)
+}
+
+// CreateRaindropV2 is CreateRaindrop, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) CreateRaindropV2(ctx context.Context, raindrop Raindrop) (*SingleRaindropResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateRaindrop(ctx, token, raindrop)
+}
+
+// UpdateRaindropV2 is UpdateRaindrop, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) UpdateRaindropV2(ctx context.Context, id uint32, patch RaindropPatch) (*SingleRaindropResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateRaindrop(ctx, token, id, patch)
+}
+
+// DeleteRaindropV2 is DeleteRaindrop, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) DeleteRaindropV2(ctx context.Context, id uint32) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	return c.DeleteRaindrop(ctx, token, id)
+}
+
+// CreateRaindropsV2 is CreateRaindrops, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) CreateRaindropsV2(ctx context.Context, raindrops []Raindrop) (*MultiRaindropsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateRaindrops(ctx, token, raindrops)
+}
+
+// UpdateRaindropsV2 is UpdateRaindrops, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) UpdateRaindropsV2(ctx context.Context, collectionID string, ids []uint32, patch RaindropPatch) (*UpdateRaindropsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateRaindrops(ctx, token, collectionID, ids, patch)
+}
+
+// DeleteRaindropsV2 is DeleteRaindrops, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) DeleteRaindropsV2(ctx context.Context, collectionID string, ids []uint32) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	return c.DeleteRaindrops(ctx, token, collectionID, ids)
+}
+
+// ListRaindropsV2 is ListRaindrops, but takes its access token from the
+// Client's TokenSource instead of a parameter. The Client must have been
+// created with NewClientWithTokenSource.
+func (c *Client) ListRaindropsV2(ctx context.Context, collectionID string, opts ...ListOption) (*MultiRaindropsResponse, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListRaindrops(ctx, token, collectionID, opts...)
+}
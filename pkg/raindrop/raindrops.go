@@ -0,0 +1,464 @@
+package raindrop
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	nethtml "golang.org/x/net/html"
+)
+
+const endpointRaindropsBatch = "/rest/v1/raindrops"
+
+// RaindropPatch represents the fields that can be changed on an existing
+// Raindrop via UpdateRaindrop / UpdateRaindrops. Unset fields (nil for
+// Important/Order, zero-valued for the rest) are omitted from the request
+// and left unchanged by the API. Important and Order are pointers so that
+// an explicit false/0 (un-starring a raindrop, or resetting its order) can
+// be distinguished from "don't change this field".
+type RaindropPatch struct {
+	Title     string   `json:"title,omitempty"`
+	Excerpt   string   `json:"excerpt,omitempty"`
+	Link      string   `json:"link,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Important *bool    `json:"important,omitempty"`
+	Order     *int     `json:"order,omitempty"`
+}
+
+// DeleteRaindropResponse represents the single-raindrop delete api response.
+type DeleteRaindropResponse struct {
+	Result bool `json:"result"`
+}
+
+// UpdateRaindropsResponse represents the batch-update raindrops api response.
+type UpdateRaindropsResponse struct {
+	Result   bool `json:"result"`
+	Modified int  `json:"modified"`
+}
+
+// DeleteRaindropsResponse represents the batch-delete raindrops api response.
+type DeleteRaindropsResponse struct {
+	Result bool `json:"result"`
+}
+
+// createRaindropsRequest represents the batch-create raindrops api request item
+type createRaindropsRequest struct {
+	Items []Raindrop `json:"items"`
+}
+
+// updateRaindropsRequest represents the batch-update raindrops api request item
+type updateRaindropsRequest struct {
+	Ids []uint32 `json:"ids"`
+	RaindropPatch
+}
+
+// deleteRaindropsRequest represents the batch-delete raindrops api request item
+type deleteRaindropsRequest struct {
+	Ids []uint32 `json:"ids"`
+}
+
+// CreateRaindrop creates a new Raindrop with arbitrary fields (title, tags,
+// collection, etc.), unlike CreateSimpleRaindrop which only takes a link.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/single#create-raindrop
+func (c *Client) CreateRaindrop(ctx context.Context, accessToken string, raindrop Raindrop) (*SingleRaindropResponse, error) {
+	u := *c.apiURL
+	u.Path = path.Join(endpointRaindrop)
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodPost, u, raindrop)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(SingleRaindropResponse)
+	if err := parseResponse(response, 200, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UpdateRaindrop updates the Raindrop with the given id, changing only the
+// fields set in patch.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/single#update-raindrop
+func (c *Client) UpdateRaindrop(ctx context.Context, accessToken string, id uint32, patch RaindropPatch) (*SingleRaindropResponse, error) {
+	u := *c.apiURL
+	u.Path = path.Join(c.apiURL.Path, endpointRaindrop, strconv.Itoa(int(id)))
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodPut, u, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(SingleRaindropResponse)
+	if err := parseResponse(response, 200, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DeleteRaindrop removes the Raindrop with the given id.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/single#remove-raindrop
+func (c *Client) DeleteRaindrop(ctx context.Context, accessToken string, id uint32) error {
+	u := *c.apiURL
+	u.Path = path.Join(c.apiURL.Path, endpointRaindrop, strconv.Itoa(int(id)))
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return err
+	}
+
+	r := new(DeleteRaindropResponse)
+	return parseResponse(response, 200, &r)
+}
+
+// CreateRaindrops creates up to 100 raindrops in a single request.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/multiple#create-many-raindrops
+func (c *Client) CreateRaindrops(ctx context.Context, accessToken string, raindrops []Raindrop) (*MultiRaindropsResponse, error) {
+	if len(raindrops) > 100 {
+		return nil, errors.New("raindrop: CreateRaindrops accepts at most 100 raindrops per call")
+	}
+
+	u := *c.apiURL
+	u.Path = path.Join(endpointRaindropsBatch)
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodPost, u, createRaindropsRequest{Items: raindrops})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(MultiRaindropsResponse)
+	if err := parseResponse(response, 200, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// UpdateRaindrops updates every raindrop in ids within collectionID,
+// changing only the fields set in patch.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/multiple#update-many-raindrops
+func (c *Client) UpdateRaindrops(ctx context.Context, accessToken string, collectionID string, ids []uint32, patch RaindropPatch) (*UpdateRaindropsResponse, error) {
+	u := *c.apiURL
+	u.Path = path.Join(c.apiURL.Path, endpointRaindrops, collectionID)
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodPut, u, updateRaindropsRequest{Ids: ids, RaindropPatch: patch})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(UpdateRaindropsResponse)
+	if err := parseResponse(response, 200, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DeleteRaindrops removes every raindrop in ids within collectionID.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/multiple#remove-many-raindrops
+func (c *Client) DeleteRaindrops(ctx context.Context, accessToken string, collectionID string, ids []uint32) error {
+	u := *c.apiURL
+	u.Path = path.Join(c.apiURL.Path, endpointRaindrops, collectionID)
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodDelete, u, deleteRaindropsRequest{Ids: ids})
+	if err != nil {
+		return err
+	}
+
+	response, err := c.execute(request)
+	if err != nil {
+		return err
+	}
+
+	r := new(DeleteRaindropsResponse)
+	return parseResponse(response, 200, &r)
+}
+
+// ListOptions configures ListRaindrops and NewRaindropIterator.
+type ListOptions struct {
+	// Page is the zero-indexed page of results to fetch.
+	Page int
+	// PerPage is the number of raindrops per page (API max is 50).
+	PerPage int
+	// Sort orders the results, e.g. "-created" (newest first) or "title".
+	Sort string
+	// Search is a pre-built search query, as produced by
+	// SearchQuery.Marshal. Empty means no filtering.
+	Search string
+	// NestedTags includes raindrops from nested collections when true.
+	NestedTags bool
+}
+
+func (o ListOptions) queryValues() url.Values {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(o.Page))
+	if o.PerPage > 0 {
+		q.Set("perpage", strconv.Itoa(o.PerPage))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if o.NestedTags {
+		q.Set("nested", "true")
+	}
+	return q
+}
+
+// ListOption configures a ListOptions passed to ListRaindrops.
+type ListOption func(*ListOptions)
+
+// WithPage sets the zero-indexed page of results to fetch.
+func WithPage(page int) ListOption {
+	return func(o *ListOptions) { o.Page = page }
+}
+
+// WithPerPage sets the number of raindrops per page (API max is 50).
+func WithPerPage(perPage int) ListOption {
+	return func(o *ListOptions) { o.PerPage = perPage }
+}
+
+// WithSort orders the results, e.g. "-created" (newest first) or "title".
+func WithSort(sort string) ListOption {
+	return func(o *ListOptions) { o.Sort = sort }
+}
+
+// WithNestedTags includes raindrops from nested collections.
+func WithNestedTags(nested bool) ListOption {
+	return func(o *ListOptions) { o.NestedTags = nested }
+}
+
+// WithSearch filters results using q, built with SearchQuery's typed
+// methods instead of a hand-assembled search string.
+func WithSearch(q *SearchQuery) ListOption {
+	return func(o *ListOptions) {
+		s, err := q.Marshal()
+		if err != nil {
+			// q.params holds only string keys and values, which
+			// json.Marshal always encodes, so this never happens in
+			// practice; leaving o.Search unset is a safe fallback.
+			return
+		}
+		o.Search = s
+	}
+}
+
+// withListOptions lets internal callers (e.g. RaindropIterator) that
+// already hold a fully-populated ListOptions pass it through the ListOption
+// API in one shot.
+func withListOptions(o ListOptions) ListOption {
+	return func(dst *ListOptions) { *dst = o }
+}
+
+// ListRaindrops fetches a single page of raindrops from collectionID ("0"
+// for all raindrops, per the API). Use NewRaindropIterator to transparently
+// page through all results instead.
+//
+// Reference: https://developer.raindrop.io/v1/raindrops/multiple#get-raindrops
+func (c *Client) ListRaindrops(ctx context.Context, accessToken string, collectionID string, opts ...ListOption) (*MultiRaindropsResponse, error) {
+	var o ListOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	u := *c.apiURL
+	u.Path = path.Join(c.apiURL.Path, endpointRaindrops, collectionID)
+
+	request, err := c.newRequest(ctx, accessToken, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.URL.RawQuery = o.queryValues().Encode()
+
+	response, err := c.execute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(MultiRaindropsResponse)
+	if err := parseResponse(response, 200, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// RaindropIterator pages through ListRaindrops results, advancing Page
+// automatically and stopping once fewer than PerPage items come back.
+type RaindropIterator struct {
+	client         *Client
+	accessToken    string
+	useTokenSource bool
+	collectionID   string
+	opts           ListOptions
+
+	items []Raindrop
+	index int
+	done  bool
+	err   error
+}
+
+// NewRaindropIterator returns a RaindropIterator over collectionID,
+// starting at opts.Page (0 if unset). opts.PerPage defaults to 50 if unset.
+func NewRaindropIterator(client *Client, accessToken string, collectionID string, opts ListOptions) *RaindropIterator {
+	if opts.PerPage <= 0 {
+		opts.PerPage = 50
+	}
+	return &RaindropIterator{client: client, accessToken: accessToken, collectionID: collectionID, opts: opts}
+}
+
+// NewRaindropIteratorV2 is NewRaindropIterator, but takes its access token
+// from client's TokenSource on every page fetch instead of a fixed
+// parameter, so a long-running iteration picks up a refreshed token. The
+// Client must have been created with NewClientWithTokenSource.
+func NewRaindropIteratorV2(client *Client, collectionID string, opts ListOptions) *RaindropIterator {
+	it := NewRaindropIterator(client, "", collectionID, opts)
+	it.useTokenSource = true
+	return it
+}
+
+// Next advances the iterator, fetching the next page of results as needed.
+// It returns false once there are no more raindrops or a request fails;
+// call Err to distinguish the two.
+func (it *RaindropIterator) Next(ctx context.Context) bool {
+	if it.index < len(it.items)-1 {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	accessToken := it.accessToken
+	if it.useTokenSource {
+		token, err := it.client.token()
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		accessToken = token
+	}
+
+	resp, err := it.client.ListRaindrops(ctx, accessToken, it.collectionID, withListOptions(it.opts))
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	it.items = resp.Items
+	it.index = 0
+	it.opts.Page++
+	if len(resp.Items) < it.opts.PerPage {
+		it.done = true
+	}
+
+	return len(it.items) > 0
+}
+
+// Raindrop returns the current Raindrop. Call only after a call to Next
+// that returned true.
+func (it *RaindropIterator) Raindrop() Raindrop {
+	return it.items[it.index]
+}
+
+// Err returns the first error encountered by Next, if any.
+func (it *RaindropIterator) Err() error {
+	return it.err
+}
+
+// TitlePrefetcher fetches metadata (currently just the page title) for a
+// link before creating a Raindrop. It's optional: by default,
+// CreateRaindrop and CreateSimpleRaindrop rely on the Raindrop.io API's own
+// pleaseParse handling instead of fetching the page client-side.
+type TitlePrefetcher func(ctx context.Context, link string) (title string, err error)
+
+// FetchTitle is a TitlePrefetcher that performs an HTTP GET of link and
+// extracts its HTML title. Callers that want the title before the API call
+// returns (rather than relying on pleaseParse) can use it to populate
+// Raindrop.Title before calling CreateRaindrop.
+func FetchTitle(ctx context.Context, link string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ePrintf("Can't close response's Body in FetchTitle: %v\n", err)
+		}
+	}()
+
+	title, ok := GetHtmlTitle(resp.Body)
+	if !ok {
+		return "", errors.New("raindrop: couldn't find an HTML title at " + link)
+	}
+
+	return title, nil
+}
+
+// GetHtmlTitle scans r for the contents of the document's <title> element.
+// It reports false if r's HTML has no title (or isn't well-formed enough to
+// find one).
+func GetHtmlTitle(r io.Reader) (string, bool) {
+	z := nethtml.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			return "", false
+		case nethtml.StartTagToken:
+			name, _ := z.TagName()
+			if string(name) != "title" {
+				continue
+			}
+			if z.Next() != nethtml.TextToken {
+				return "", false
+			}
+			return strings.TrimSpace(string(z.Text())), true
+		}
+	}
+}
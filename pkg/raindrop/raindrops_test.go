@@ -0,0 +1,358 @@
+package raindrop
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestCreateRaindrop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v1/raindrop" {
+			t.Errorf("request = %s %s, want POST /rest/v1/raindrop", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer a-token" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer a-token")
+		}
+
+		var got Raindrop
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if got.Link != "https://example.com" {
+			t.Errorf("request Link = %q, want %q", got.Link, "https://example.com")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SingleRaindropResponse{Result: true, Items: Raindrop{Link: got.Link, Title: "Example"}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	resp, err := client.CreateRaindrop(context.Background(), "a-token", Raindrop{Link: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateRaindrop() returned error: %v", err)
+	}
+	if resp.Items.Title != "Example" {
+		t.Errorf("CreateRaindrop() Items.Title = %q, want %q", resp.Items.Title, "Example")
+	}
+}
+
+func TestRaindropIteratorNext(t *testing.T) {
+	pages := [][]Raindrop{
+		{{Title: "one"}, {Title: "two"}},
+		{{Title: "three"}},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests >= len(pages) {
+			t.Fatalf("got %d requests, want at most %d", requests+1, len(pages))
+		}
+		items := pages[requests]
+		requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: items})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	it := NewRaindropIterator(client, "a-token", "0", ListOptions{PerPage: 2})
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Raindrop().Title)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	want := []string{"one", "two", "three"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2 (first page full at PerPage, second page short)", requests)
+	}
+}
+
+func TestRaindropIteratorNextExactPerPageBoundary(t *testing.T) {
+	// A page that comes back exactly PerPage items long must not be
+	// mistaken for the last page: the iterator should fetch one more page
+	// to confirm there's nothing after it.
+	pages := [][]Raindrop{
+		{{Title: "one"}, {Title: "two"}},
+		{},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := pages[requests]
+		requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: items})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	it := NewRaindropIterator(client, "a-token", "0", ListOptions{PerPage: 2})
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Raindrop().Title)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	if want := []string{"one", "two"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2 (full page, then an empty page confirming the end)", requests)
+	}
+}
+
+func TestUpdateRaindrop(t *testing.T) {
+	important := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/v1/raindrop/42" {
+			t.Errorf("request = %s %s, want PUT /rest/v1/raindrop/42", r.Method, r.URL.Path)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		// important:false must be distinguishable from an unset field, so
+		// it needs to actually be present in the serialized request.
+		if !strings.Contains(string(body), `"important":false`) {
+			t.Errorf("request body = %s, want it to contain %q", body, `"important":false`)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SingleRaindropResponse{Result: true, Items: Raindrop{Link: "https://example.com", Title: "Updated"}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	resp, err := client.UpdateRaindrop(context.Background(), "a-token", 42, RaindropPatch{Important: &important})
+	if err != nil {
+		t.Fatalf("UpdateRaindrop() returned error: %v", err)
+	}
+	if resp.Items.Title != "Updated" {
+		t.Errorf("UpdateRaindrop() Items.Title = %q, want %q", resp.Items.Title, "Updated")
+	}
+}
+
+func TestDeleteRaindrop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/rest/v1/raindrop/42" {
+			t.Errorf("request = %s %s, want DELETE /rest/v1/raindrop/42", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeleteRaindropResponse{Result: true})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if err := client.DeleteRaindrop(context.Background(), "a-token", 42); err != nil {
+		t.Fatalf("DeleteRaindrop() returned error: %v", err)
+	}
+}
+
+func TestCreateRaindrops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v1/raindrops" {
+			t.Errorf("request = %s %s, want POST /rest/v1/raindrops", r.Method, r.URL.Path)
+		}
+
+		var got createRaindropsRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if len(got.Items) != 2 {
+			t.Errorf("request Items = %+v, want 2 items", got.Items)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: got.Items})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	resp, err := client.CreateRaindrops(context.Background(), "a-token", []Raindrop{{Link: "https://a.example"}, {Link: "https://b.example"}})
+	if err != nil {
+		t.Fatalf("CreateRaindrops() returned error: %v", err)
+	}
+	if len(resp.Items) != 2 {
+		t.Errorf("CreateRaindrops() Items = %+v, want 2 items", resp.Items)
+	}
+}
+
+func TestCreateRaindropsTooMany(t *testing.T) {
+	raindrops := make([]Raindrop, 101)
+
+	client, err := NewClient("id", "secret", "redirect")
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if _, err := client.CreateRaindrops(context.Background(), "a-token", raindrops); err == nil {
+		t.Error("CreateRaindrops() with 101 raindrops returned nil error, want error")
+	}
+}
+
+func TestUpdateRaindrops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/v1/raindrops/5" {
+			t.Errorf("request = %s %s, want PUT /rest/v1/raindrops/5", r.Method, r.URL.Path)
+		}
+
+		var got updateRaindropsRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if len(got.Ids) != 2 || got.Ids[0] != 1 || got.Ids[1] != 2 {
+			t.Errorf("request Ids = %v, want [1 2]", got.Ids)
+		}
+		if got.Title != "bulk title" {
+			t.Errorf("request Title = %q, want %q", got.Title, "bulk title")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UpdateRaindropsResponse{Result: true, Modified: 2})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	resp, err := client.UpdateRaindrops(context.Background(), "a-token", "5", []uint32{1, 2}, RaindropPatch{Title: "bulk title"})
+	if err != nil {
+		t.Fatalf("UpdateRaindrops() returned error: %v", err)
+	}
+	if resp.Modified != 2 {
+		t.Errorf("UpdateRaindrops() Modified = %d, want 2", resp.Modified)
+	}
+}
+
+func TestDeleteRaindrops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/rest/v1/raindrops/5" {
+			t.Errorf("request = %s %s, want DELETE /rest/v1/raindrops/5", r.Method, r.URL.Path)
+		}
+
+		var got deleteRaindropsRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if len(got.Ids) != 2 || got.Ids[0] != 1 || got.Ids[1] != 2 {
+			t.Errorf("request Ids = %v, want [1 2]", got.Ids)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeleteRaindropsResponse{Result: true})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if err := client.DeleteRaindrops(context.Background(), "a-token", "5", []uint32{1, 2}); err != nil {
+		t.Fatalf("DeleteRaindrops() returned error: %v", err)
+	}
+}
+
+func TestListRaindrops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/v1/raindrops/0" {
+			t.Errorf("request = %s %s, want GET /rest/v1/raindrops/0", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("perpage"); got != "25" {
+			t.Errorf("perpage query param = %q, want %q", got, "25")
+		}
+		if got := r.URL.Query().Get("sort"); got != "-created" {
+			t.Errorf("sort query param = %q, want %q", got, "-created")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MultiRaindropsResponse{Result: true, Items: []Raindrop{{Title: "one"}}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("id", "secret", "redirect", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	resp, err := client.ListRaindrops(context.Background(), "a-token", "0", WithPerPage(25), WithSort("-created"))
+	if err != nil {
+		t.Fatalf("ListRaindrops() returned error: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Title != "one" {
+		t.Errorf("ListRaindrops() Items = %+v, want one raindrop titled %q", resp.Items, "one")
+	}
+}
+
+func TestGetHtmlTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		wantTitle string
+		wantOk    bool
+	}{
+		{name: "simple", html: `<html><head><title>Example Page</title></head><body></body></html>`, wantTitle: "Example Page", wantOk: true},
+		{name: "whitespace trimmed", html: `<html><head><title>  Spacey  </title></head></html>`, wantTitle: "Spacey", wantOk: true},
+		{name: "no title", html: `<html><head></head><body>hi</body></html>`, wantTitle: "", wantOk: false},
+		{name: "empty title", html: `<html><head><title></title></head></html>`, wantTitle: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, ok := GetHtmlTitle(strings.NewReader(tt.html))
+			if ok != tt.wantOk || title != tt.wantTitle {
+				t.Errorf("GetHtmlTitle() = (%q, %v), want (%q, %v)", title, ok, tt.wantTitle, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package raindrop
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RaindropType enumerates the Raindrop.io content types recognized by the
+// type: search filter.
+type RaindropType string
+
+const (
+	RaindropTypeLink     RaindropType = "link"
+	RaindropTypeArticle  RaindropType = "article"
+	RaindropTypeImage    RaindropType = "image"
+	RaindropTypeVideo    RaindropType = "video"
+	RaindropTypeDocument RaindropType = "document"
+	RaindropTypeAudio    RaindropType = "audio"
+)
+
+// MatchMode controls how a SearchQuery's terms are combined.
+type MatchMode string
+
+const (
+	// MatchAll requires every term to match (the API's default).
+	MatchAll MatchMode = "AND"
+	// MatchAny requires at least one term to match.
+	MatchAny MatchMode = "OR"
+)
+
+const searchDateFormat = "2006-01-02"
+
+// searchParam is a single key/val entry in Raindrop's JSON search DSL.
+type searchParam struct {
+	Key string `json:"key"`
+	Val string `json:"val"`
+}
+
+// SearchQuery builds a Raindrop.io search query: either the JSON array the
+// API expects in its "search" parameter (Marshal), or the equivalent
+// human-readable search-bar syntax (String), for logging or reuse.
+//
+// The zero value is an empty query. Methods return the receiver so calls
+// can be chained: new(SearchQuery).Tag("go").Important(true).
+type SearchQuery struct {
+	params []searchParam
+	terms  []string
+	match  MatchMode
+}
+
+func (q *SearchQuery) add(key, val, term string) *SearchQuery {
+	q.params = append(q.params, searchParam{Key: key, Val: val})
+	q.terms = append(q.terms, term)
+	return q
+}
+
+// Word adds a free-text term.
+func (q *SearchQuery) Word(word string) *SearchQuery {
+	return q.add("word", word, word)
+}
+
+// Tag restricts results to raindrops tagged tag.
+func (q *SearchQuery) Tag(tag string) *SearchQuery {
+	return q.add("tag", tag, "#"+tag)
+}
+
+// NotTag excludes raindrops tagged tag.
+func (q *SearchQuery) NotTag(tag string) *SearchQuery {
+	return q.add("tag", "-"+tag, "-#"+tag)
+}
+
+// Link restricts results to raindrops whose link contains link.
+func (q *SearchQuery) Link(link string) *SearchQuery {
+	return q.add("link", link, "link:"+link)
+}
+
+// Type restricts results to raindrops of the given RaindropType.
+func (q *SearchQuery) Type(t RaindropType) *SearchQuery {
+	return q.add("type", string(t), "type:"+string(t))
+}
+
+// CreatedAfter restricts results to raindrops created after t.
+func (q *SearchQuery) CreatedAfter(t time.Time) *SearchQuery {
+	d := t.Format(searchDateFormat)
+	return q.add("created", ">"+d, "created:>"+d)
+}
+
+// CreatedBefore restricts results to raindrops created before t.
+func (q *SearchQuery) CreatedBefore(t time.Time) *SearchQuery {
+	d := t.Format(searchDateFormat)
+	return q.add("created", "<"+d, "created:<"+d)
+}
+
+// Important restricts results to raindrops marked important (or, with
+// important=false, not marked important).
+func (q *SearchQuery) Important(important bool) *SearchQuery {
+	return q.add("important", strconv.FormatBool(important), "important:"+strconv.FormatBool(important))
+}
+
+// Broken restricts results to raindrops the API has flagged as broken
+// links (or, with broken=false, raindrops it hasn't).
+func (q *SearchQuery) Broken(broken bool) *SearchQuery {
+	return q.add("broken", strconv.FormatBool(broken), "broken:"+strconv.FormatBool(broken))
+}
+
+// Duplicate restricts results to raindrops the API has flagged as
+// duplicates (or, with duplicate=false, raindrops it hasn't).
+func (q *SearchQuery) Duplicate(duplicate bool) *SearchQuery {
+	return q.add("duplicate", strconv.FormatBool(duplicate), "duplicate:"+strconv.FormatBool(duplicate))
+}
+
+// Match sets how this query's terms are combined. The API's default is
+// MatchAll.
+func (q *SearchQuery) Match(mode MatchMode) *SearchQuery {
+	q.match = mode
+	return q
+}
+
+// Marshal returns the JSON-encoded search array the Raindrop.io API expects
+// in its "search" query parameter.
+func (q *SearchQuery) Marshal() (string, error) {
+	params := q.params
+	if q.match == MatchAny {
+		params = append(append([]searchParam{}, q.params...), searchParam{Key: "match", Val: "OR"})
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// String returns the equivalent human-readable search-bar syntax, e.g.
+// "#go important:true match:OR", for logging or reuse in Raindrop's UI.
+func (q *SearchQuery) String() string {
+	parts := append([]string{}, q.terms...)
+	if q.match == MatchAny {
+		parts = append(parts, "match:OR")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,72 @@
+package raindrop
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSearchQueryMarshal(t *testing.T) {
+	q := new(SearchQuery).Tag("go").Important(true)
+
+	got, err := q.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	want := `[{"key":"tag","val":"go"},{"key":"important","val":"true"}]`
+	if got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestSearchQueryMarshalMatchAny(t *testing.T) {
+	q := new(SearchQuery).Tag("go").Tag("rust").Match(MatchAny)
+
+	got, err := q.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	want := `[{"key":"tag","val":"go"},{"key":"tag","val":"rust"},{"key":"match","val":"OR"}]`
+	if got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestSearchQueryString(t *testing.T) {
+	q := new(SearchQuery).Tag("go").NotTag("archived").Important(true).Match(MatchAny)
+
+	got := q.String()
+	want := "#go -#archived important:true match:OR"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchQueryMarshalEscapesQuotes(t *testing.T) {
+	q := new(SearchQuery).Word(`she said "hi"`)
+
+	got, err := q.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	want := `[{"key":"word","val":"she said \"hi\""}]`
+	if got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var decoded []map[string]string
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Marshal() produced invalid JSON: %v", err)
+	}
+}
+
+func TestSearchQueryCreatedDates(t *testing.T) {
+	d := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	q := new(SearchQuery).CreatedAfter(d).CreatedBefore(d)
+
+	got := q.String()
+	want := "created:>2024-03-15 created:<2024-03-15"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
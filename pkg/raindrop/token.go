@@ -0,0 +1,179 @@
+package raindrop
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// defaultRefreshLeeway is a leeway for NewReuseTokenSource: with it, a Token
+// is refreshed once it is within one minute of its Expiry.
+const defaultRefreshLeeway = 60 * time.Second
+
+// Token is an OAuth2 access/refresh token pair for the Raindrop.io API,
+// modeled on golang.org/x/oauth2.Token.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Valid reports whether t has an access token and isn't past its Expiry.
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && time.Now().Before(t.Expiry)
+}
+
+// tokenFromResponse converts an AccessTokenResponse (as returned by
+// GetAccessToken / RefreshAccessToken) into a Token, computing Expiry from
+// ExpiresIn at receipt time.
+func tokenFromResponse(r *AccessTokenResponse) *Token {
+	return &Token{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(r.ExpiresIn) * time.Second),
+	}
+}
+
+// TokenSource supplies a Token on demand. Implementations are expected to
+// cache and refresh as needed; callers should call Token() before every
+// request rather than caching the result themselves.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// staticTokenSource always returns the same Token.
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token() (*Token, error) {
+	return s.token, nil
+}
+
+// StaticTokenSource returns a TokenSource that always returns t verbatim.
+// Useful for tokens refreshed out-of-band, or in tests.
+func StaticTokenSource(t *Token) TokenSource {
+	return staticTokenSource{token: t}
+}
+
+// reuseTokenSource wraps a seed Token, caching it behind a mutex and
+// transparently refreshing it against client shortly before it expires.
+type reuseTokenSource struct {
+	mu     sync.Mutex
+	client *Client
+	token  *Token
+	leeway time.Duration
+	store  TokenStore
+}
+
+// NewReuseTokenSource returns a TokenSource that reuses token until it's
+// within leeway of expiring, then refreshes it against client. If store is
+// non-nil, refreshed tokens are persisted to it. NewClientWithTokenSource
+// does not wrap its source in one; it uses the given TokenSource as is.
+func NewReuseTokenSource(client *Client, token *Token, leeway time.Duration, store TokenStore) TokenSource {
+	return &reuseTokenSource{client: client, token: token, leeway: leeway, store: store}
+}
+
+func (s *reuseTokenSource) Token() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Until(s.token.Expiry) > s.leeway {
+		return s.token, nil
+	}
+	if s.token == nil || s.token.RefreshToken == "" {
+		return nil, errors.New("raindrop: token expired and no refresh token is available")
+	}
+
+	resp, err := s.client.RefreshAccessToken(context.Background(), s.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New("raindrop: refreshing access token: " + resp.Error)
+	}
+
+	refreshed := tokenFromResponse(resp)
+	if refreshed.RefreshToken == "" {
+		// The API doesn't always return a new refresh token; keep the old one.
+		refreshed.RefreshToken = s.token.RefreshToken
+	}
+	s.token = refreshed
+
+	if s.store != nil {
+		if err := s.store.Save(s.token); err != nil {
+			s.client.logf("Can't save refreshed token: %v\n", err)
+		}
+	}
+
+	return s.token, nil
+}
+
+// TokenStore persists a Token so long-lived processes survive restarts
+// without sending the user through authorization again.
+type TokenStore interface {
+	Load() (*Token, error)
+	Save(*Token) error
+}
+
+// FileTokenStore is a TokenStore that persists the Token as JSON in a file
+// at Path.
+type FileTokenStore struct {
+	Path string
+}
+
+// NewFileTokenStore returns a FileTokenStore backed by the file at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// Load reads and decodes the Token stored at Path.
+func (f *FileTokenStore) Load() (*Token, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Token)
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Save encodes token as JSON and writes it to Path.
+func (f *FileTokenStore) Save(token *Token) error {
+	b, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, b, 0600)
+}
+
+// NewClientWithTokenSource creates a Raindrop.io client that obtains its
+// access token from source instead of requiring callers to pass one to
+// every method. Pair this with the V2 methods (e.g. GetRootCollectionsV2),
+// which take the access token from source rather than as a parameter.
+func NewClientWithTokenSource(clientId string, clientSecret string, redirectUri string, source TokenSource, opts ...ClientOption) (*Client, error) {
+	c, err := NewClient(clientId, clientSecret, redirectUri, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.tokenSource = source
+	return c, nil
+}
+
+// token returns the current access token from the Client's TokenSource.
+func (c *Client) token() (string, error) {
+	if c.tokenSource == nil {
+		return "", errors.New("raindrop: client has no TokenSource; create it with NewClientWithTokenSource")
+	}
+	t, err := c.tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return t.AccessToken, nil
+}
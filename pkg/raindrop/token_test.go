@@ -0,0 +1,78 @@
+package raindrop
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenValid(t *testing.T) {
+	cases := []struct {
+		name string
+		tok  *Token
+		want bool
+	}{
+		{"nil token", nil, false},
+		{"no access token", &Token{Expiry: time.Now().Add(time.Hour)}, false},
+		{"expired", &Token{AccessToken: "t", Expiry: time.Now().Add(-time.Hour)}, false},
+		{"valid", &Token{AccessToken: "t", Expiry: time.Now().Add(time.Hour)}, true},
+	}
+	for _, c := range cases {
+		if got := c.tok.Valid(); got != c.want {
+			t.Errorf("%s: Valid() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	want := &Token{AccessToken: "abc123"}
+	src := StaticTokenSource(want)
+
+	got, err := src.Token()
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Token() = %v, want %v", got, want)
+	}
+}
+
+func TestReuseTokenSourceReturnsCachedTokenBeforeLeeway(t *testing.T) {
+	tok := &Token{AccessToken: "cached", Expiry: time.Now().Add(time.Hour)}
+	src := NewReuseTokenSource(nil, tok, defaultRefreshLeeway, nil)
+
+	got, err := src.Token()
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if got.AccessToken != "cached" {
+		t.Errorf("Token().AccessToken = %q, want %q", got.AccessToken, "cached")
+	}
+}
+
+func TestReuseTokenSourceErrorsWithoutRefreshToken(t *testing.T) {
+	tok := &Token{AccessToken: "expiring", Expiry: time.Now().Add(-time.Minute)}
+	src := NewReuseTokenSource(nil, tok, defaultRefreshLeeway, nil)
+
+	if _, err := src.Token(); err == nil {
+		t.Error("Token() with an expired token and no refresh token returned nil error")
+	}
+}
+
+func TestFileTokenStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	store := NewFileTokenStore(path)
+
+	want := &Token{AccessToken: "abc", RefreshToken: "xyz", Expiry: time.Now().Add(time.Hour).Truncate(time.Second)}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.Expiry.Equal(want.Expiry) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package raindrop
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RetryPolicy configures the backoff RetryTransport uses when retrying
+// rate-limited or server-error responses.
+type RetryPolicy struct {
+	// InitialInterval is the backoff before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the exponential backoff between retries before
+	// Jitter is added. A Retry-After or X-RateLimit-Reset wait can be
+	// longer.
+	MaxInterval time.Duration
+	// MaxRetries is the number of retry attempts before giving up.
+	MaxRetries int
+	// Jitter is the fraction (0-1) of additional random delay added to each
+	// backoff interval, to avoid retry storms from many clients at once.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used by NewClient when no WithRetryPolicy option is
+// given.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     30 * time.Second,
+		MaxRetries:      5,
+		Jitter:          0.2,
+	}
+}
+
+// RateLimiter throttles outgoing requests before they're sent. A
+// *golang.org/x/time/rate.Limiter satisfies this interface and can be
+// passed directly to WithRateLimiter.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimitError is returned when RetryTransport exhausts its retries
+// against a 429 Too Many Requests response.
+type RateLimitError struct {
+	// Retries is the number of retry attempts made before giving up.
+	Retries int
+	// RetryAfter is the duration the server asked the client to wait,
+	// parsed from the Retry-After header of the last response.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("raindrop: rate limit exceeded after %d retries (retry after %s)", e.Retries, e.RetryAfter)
+}
+
+// RetryTransport wraps an http.RoundTripper, retrying 429 and 5xx responses
+// with exponential backoff and honoring the Raindrop API's rate-limit
+// headers (X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After).
+//
+// Reference: https://developer.raindrop.io/#rate-limiting
+type RetryTransport struct {
+	// Base is the underlying RoundTripper. http.DefaultTransport is used if
+	// nil.
+	Base http.RoundTripper
+	// Policy controls the retry backoff.
+	Policy RetryPolicy
+	// RateLimiter, if set, is waited on before every request so the client
+	// stays under the API's rate limit proactively instead of reacting to
+	// 429s after the fact.
+	RateLimiter RateLimiter
+}
+
+// NewRetryTransport wraps base (http.DefaultTransport if nil) with the
+// given RetryPolicy.
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{Base: base, Policy: policy}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.RateLimiter != nil {
+		if err := t.RateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for retries := 0; ; retries++ {
+		if bodyBytes != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err = t.Base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if retries >= t.Policy.MaxRetries {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				_ = resp.Body.Close()
+				return nil, &RateLimitError{Retries: retries, RetryAfter: retryAfterDuration(resp.Header)}
+			}
+			return resp, nil
+		}
+
+		wait := backoffInterval(t.Policy, retries)
+		if remaining, ok := parseIntHeader(resp.Header, "X-RateLimit-Remaining"); ok && remaining == 0 {
+			if reset, ok := parseRateLimitReset(resp.Header); ok {
+				if untilReset := time.Until(reset); untilReset > wait {
+					wait = untilReset
+				}
+			}
+		}
+		if ra := retryAfterDuration(resp.Header); ra > wait {
+			wait = ra
+		}
+
+		_ = resp.Body.Close()
+		if !sleepOrDone(req.Context(), wait) {
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func backoffInterval(p RetryPolicy, attempt int) time.Duration {
+	interval := p.InitialInterval * time.Duration(1<<uint(attempt))
+	if interval <= 0 || interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	if p.Jitter > 0 {
+		interval += time.Duration(rand.Float64() * p.Jitter * float64(interval))
+	}
+	return interval
+}
+
+func retryAfterDuration(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func parseIntHeader(h http.Header, name string) (int, bool) {
+	v := h.Get(name)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func parseRateLimitReset(h http.Header) (time.Time, bool) {
+	n, ok := parseIntHeader(h, "X-RateLimit-Reset")
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(n), 0), true
+}
+
+func sleepOrDone(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package raindrop
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	policy := RetryPolicy{InitialInterval: time.Second, MaxInterval: 10 * time.Second, Jitter: 0}
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 1 * time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second}, // would be 16s, capped at MaxInterval
+	}
+	for _, c := range cases {
+		if got := backoffInterval(policy, c.attempt); got != c.want {
+			t.Errorf("backoffInterval(attempt=%d) = %s, want %s", c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestBackoffIntervalJitterStaysWithinBounds(t *testing.T) {
+	policy := RetryPolicy{InitialInterval: time.Second, MaxInterval: 10 * time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		got := backoffInterval(policy, 0)
+		if got < time.Second || got > 1500*time.Millisecond {
+			t.Fatalf("backoffInterval with jitter = %s, want between 1s and 1.5s", got)
+		}
+	}
+}
+
+func TestRetryAfterDuration(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "30")
+	if got, want := retryAfterDuration(h), 30*time.Second; got != want {
+		t.Errorf("retryAfterDuration(%q) = %s, want %s", "30", got, want)
+	}
+
+	if got := retryAfterDuration(http.Header{}); got != 0 {
+		t.Errorf("retryAfterDuration(empty) = %s, want 0", got)
+	}
+}
+
+func TestParseIntHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "42")
+
+	n, ok := parseIntHeader(h, "X-RateLimit-Remaining")
+	if !ok || n != 42 {
+		t.Errorf("parseIntHeader = (%d, %v), want (42, true)", n, ok)
+	}
+
+	if _, ok := parseIntHeader(h, "X-Missing"); ok {
+		t.Error("parseIntHeader for missing header returned ok=true")
+	}
+}